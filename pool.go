@@ -0,0 +1,101 @@
+package runebuffer
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+const (
+	minPoolBufferSize = 64
+	maxPoolBufferSize = 1 << 16
+)
+
+// bufferPool holds a sync.Pool of RuneBuffers all sized to the same bucket.
+type bufferPool struct {
+	size int
+	pool sync.Pool
+}
+
+var bufferPools = newBufferPools()
+
+func newBufferPools() []*bufferPool {
+	var pools []*bufferPool
+	for size := minPoolBufferSize; size <= maxPoolBufferSize; size *= 2 {
+		size := size
+		pools = append(pools, &bufferPool{
+			size: size,
+			pool: sync.Pool{
+				New: func() any {
+					return &RuneBuffer{
+						br:      bufio.NewReader(nil),
+						buf:     make([]rune, size),
+						posRing: make([]Position, size),
+						pos:     Position{Line: 1},
+					}
+				},
+			},
+		})
+	}
+	return pools
+}
+
+// bucketSize rounds n up to the nearest power-of-two bucket.
+func bucketSize(n int) int {
+	size := minPoolBufferSize
+	for size < n {
+		size *= 2
+	}
+	return size
+}
+
+// poolFor returns the bucket pool for size, or nil if size exceeds
+// maxPoolBufferSize and so is never pooled.
+func poolFor(size int) *bufferPool {
+	bucket := bucketSize(size)
+	if bucket > maxPoolBufferSize {
+		return nil
+	}
+	for _, bp := range bufferPools {
+		if bp.size == bucket {
+			return bp
+		}
+	}
+	return nil
+}
+
+// GetBuffer returns a pooled RuneBuffer of DefaultBufferSize reading from r.
+// The returned buffer must be returned to the pool with PutBuffer, and must
+// not be retained after that call.
+func GetBuffer(r io.Reader) *RuneBuffer {
+	return GetBufferSize(r, DefaultBufferSize)
+}
+
+// GetBufferSize returns a pooled RuneBuffer with capacity >= size (rounded up
+// to the nearest bucket) reading from r. If size exceeds the largest pooled
+// bucket, a fresh, unpooled RuneBuffer is allocated instead. The returned
+// buffer must be returned to the pool with PutBuffer, and must not be
+// retained after that call.
+func GetBufferSize(r io.Reader, size int) *RuneBuffer {
+	bp := poolFor(size)
+	if bp == nil {
+		return NewRuneBufferWithSize(r, size)
+	}
+	b := bp.pool.Get().(*RuneBuffer)
+	b.Reset(r)
+	return b
+}
+
+// PutBuffer resets b and returns it to its size bucket's pool for reuse, if
+// its capacity is exactly one of the bucket sizes; any other buffer (for
+// example one resized by Grow) is dropped. The underlying reader reference
+// is cleared so it isn't pinned in memory. Callers must not use b again after calling PutBuffer.
+func PutBuffer(b *RuneBuffer) {
+	if b == nil {
+		return
+	}
+	b.Reset(nil)
+	if bp := poolFor(len(b.buf)); bp != nil && bp.size == len(b.buf) {
+		bp.pool.Put(b)
+	}
+}
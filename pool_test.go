@@ -0,0 +1,82 @@
+package runebuffer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("GetBuffer returns a usable buffer sized to DefaultBufferSize", func(t *testing.T) {
+		b := GetBuffer(strings.NewReader("hello"))
+		defer PutBuffer(b)
+
+		assert.Equal(t, DefaultBufferSize, len(b.buf))
+		read, _, err := b.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'h', read)
+	})
+
+	t.Run("GetBufferSize rounds up to the nearest bucket", func(t *testing.T) {
+		b := GetBufferSize(strings.NewReader("hello"), 100)
+		defer PutBuffer(b)
+
+		assert.Equal(t, 128, len(b.buf))
+	})
+
+	t.Run("PutBuffer recycles a buffer for a later Get of the same bucket", func(t *testing.T) {
+		b := GetBufferSize(strings.NewReader("hello"), 64)
+		ptr := b
+		PutBuffer(b)
+
+		again := GetBufferSize(strings.NewReader("world"), 64)
+		defer PutBuffer(again)
+
+		assert.Same(t, ptr, again)
+		read, _, err := again.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'w', read)
+	})
+
+	t.Run("GetBufferSize beyond the largest bucket allocates an unpooled buffer", func(t *testing.T) {
+		b := GetBufferSize(strings.NewReader("hello"), maxPoolBufferSize+1)
+		assert.Equal(t, maxPoolBufferSize+1, len(b.buf))
+	})
+
+	t.Run("Reset re-aims an existing buffer without reallocating", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB"), 4)
+		_, _, _ = ring.ReadRune()
+
+		buf := ring.buf
+		ring.Reset(strings.NewReader("CD"))
+
+		assert.Same(t, &buf[0], &ring.buf[0])
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'C', read)
+	})
+
+	t.Run("Reset clears a previous caller's AutoGrow/MaxSize settings", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB"), 4)
+		ring.AutoGrow = true
+		ring.MaxSize = 100
+
+		ring.Reset(strings.NewReader("CD"))
+
+		assert.False(t, ring.AutoGrow)
+		assert.Equal(t, 0, ring.MaxSize)
+	})
+
+	t.Run("PutBuffer does not leak AutoGrow/MaxSize into the next Get from the same bucket", func(t *testing.T) {
+		b := GetBufferSize(strings.NewReader("hello"), 64)
+		b.AutoGrow = true
+		b.MaxSize = 100
+		PutBuffer(b)
+
+		again := GetBufferSize(strings.NewReader("world"), 64)
+		defer PutBuffer(again)
+
+		assert.False(t, again.AutoGrow)
+		assert.Equal(t, 0, again.MaxSize)
+	})
+}
@@ -2,22 +2,67 @@ package runebuffer
 
 import (
 	"bufio"
+	"errors"
 	"io"
+	"strings"
 	"sync"
+	"unicode/utf8"
 )
 
 const (
 	DefaultBufferSize = 1024
 )
 
+// ErrInvalidUnreadRune is returned by UnreadRune when there is no rune to
+// unread, either because the last ReadRune failed or has already been undone
+// by an unread, or because the ring has no unread capacity left to restore
+// into.
+var ErrInvalidUnreadRune = errors.New("runebuffer: invalid use of UnreadRune")
+
+// ErrBufferFull is returned when a requested lookahead or delimited read
+// would require more capacity than the ring provides.
+var ErrBufferFull = errors.New("runebuffer: buffer full")
+
+// ErrNegativeCount is returned by PeekRunes when given a negative count,
+// matching bufio.Reader's handling of invalid negative sizes.
+var ErrNegativeCount = errors.New("runebuffer: negative count")
+
+var _ io.RuneScanner = (*RuneBuffer)(nil)
+
+// Position describes a location within the stream as an absolute rune offset
+// plus a 1-indexed line and 0-indexed column, suitable for reporting to a
+// lexer's caller.
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
 // RuneBuffer adds an extra layer of buffering on top of bufio.Reader that works entirely with runes.
 // This enables multiple UnreadRune calls without an intermediate read operation.
 // This is a ring buffer, so the max number of UnreadRune calls will always be <= DefaultBufferSize (or the size passed to NewRuneBufferWithSize).
+//
+// RuneBuffer also doubles as a lexer scanning surface: pending tracks the
+// length of the rune span accumulated since the last Emit or Discard, so
+// callers can read ahead with ReadRune (looking further with PeekRunes) and
+// later retrieve or drop what they scanned.
 type RuneBuffer struct {
+	// AutoGrow, when true, makes UnreadNumRunes call Grow first when the
+	// requested count would not fit in the ring's capacity. Runes the ring no
+	// longer holds are not recovered by growing.
+	AutoGrow bool
+	// MaxSize caps how large AutoGrow is allowed to grow the ring. Zero means
+	// unbounded.
+	MaxSize int
+
 	br           *bufio.Reader
 	rptr, wptr   int
+	pending      int
 	buf          []rune
 	size, unread int
+	pos          Position
+	posRing      []Position
+	canUnread    bool
 }
 
 func NewRuneBuffer(r io.Reader) *RuneBuffer {
@@ -29,54 +74,331 @@ func NewRuneBufferWithSize(r io.Reader, size int) *RuneBuffer {
 		return nil
 	}
 	return &RuneBuffer{
-		br:  bufio.NewReader(r),
-		buf: make([]rune, size),
+		br:      bufio.NewReader(r),
+		buf:     make([]rune, size),
+		posRing: make([]Position, size),
+		pos:     Position{Line: 1},
 	}
 }
 
-// ReadRune will read the next rune in the buffer, pulling from the io.Reader if necessary.
-func (b *RuneBuffer) ReadRune() (rune, error) {
+// Reset re-aims the buffer at a new source, clearing all ring and scanning
+// state, as well as the AutoGrow/MaxSize policy, so the buffer can be reused
+// without reallocating its backing slices or leaking a previous caller's
+// settings. Passing a nil reader releases the previous one without arming the
+// buffer for further reads, which is what PutBuffer relies on to avoid
+// pinning it.
+func (b *RuneBuffer) Reset(r io.Reader) {
+	b.br.Reset(r)
+	b.rptr = 0
+	b.wptr = 0
+	b.pending = 0
+	b.size = 0
+	b.unread = 0
+	b.pos = Position{Line: 1}
+	b.canUnread = false
+	b.AutoGrow = false
+	b.MaxSize = 0
+}
+
+// ReadRune reads and returns the next rune from the buffer along with its
+// size in bytes, pulling from the io.Reader if necessary. It returns io.EOF
+// once the underlying reader and the ring are both drained, satisfying
+// io.RuneReader.
+func (b *RuneBuffer) ReadRune() (r rune, size int, err error) {
+	b.canUnread = false
 	if b.unread == 0 {
 		if b.wptr == -1 {
-			return 0, nil
+			return 0, 0, io.EOF
 		}
 		if err := b.readStream(); err != nil {
-			return 0, err
+			return 0, 0, err
 		}
 	}
 	val := b.buf[b.rptr]
+	b.posRing[b.rptr] = b.pos
+	b.pos = advancePosition(b.pos, val)
 	b.incrementRptr()
 	b.decrementUnread()
-	return val, nil
+	if b.pending < len(b.buf) {
+		b.pending++
+	}
+	b.canUnread = true
+	return val, utf8.RuneLen(val), nil
 }
 
-// UnreadRune will unread the previously read rune, if it exists.
+// PeekRunes returns the next n runes without consuming them, pulling from the
+// io.Reader to fill the ring as needed. A short read is returned with no
+// error if the stream reaches EOF before n runes are available. PeekRunes
+// fails with ErrBufferFull if n exceeds the ring's capacity, rather than
+// silently truncating, and with ErrNegativeCount if n is negative.
+func (b *RuneBuffer) PeekRunes(n int) ([]rune, error) {
+	if n < 0 {
+		return nil, ErrNegativeCount
+	}
+	if n > len(b.buf) {
+		return nil, ErrBufferFull
+	}
+	for b.unread < n && b.wptr != -1 {
+		if err := b.readStream(); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+	}
+	avail := b.unread
+	if n < avail {
+		avail = n
+	}
+	out := make([]rune, avail)
+	p := b.rptr
+	for i := 0; i < avail; i++ {
+		out[i] = b.buf[p]
+		p = b.normalizePtr(p + 1)
+	}
+	return out, nil
+}
+
+// ReadRuneSlice reads until and including the first occurrence of delim,
+// returning the runes read. It mirrors bufio.Reader.ReadSlice: if delim is
+// not found before the ring fills, it returns the runes read so far along
+// with ErrBufferFull; if the stream ends first, it returns the runes read so
+// far along with io.EOF.
+func (b *RuneBuffer) ReadRuneSlice(delim rune) ([]rune, error) {
+	out := make([]rune, 0, len(b.buf))
+	for {
+		r, _, err := b.ReadRune()
+		if err != nil {
+			return out, err
+		}
+		out = append(out, r)
+		if r == delim {
+			return out, nil
+		}
+		if len(out) >= len(b.buf) {
+			return out, ErrBufferFull
+		}
+	}
+}
+
+// ReadString reads until and including the first occurrence of delim,
+// returning the data read as a string. It mirrors bufio.Reader.ReadString:
+// if ReadString encounters an error before finding delim, it returns the data
+// read so far along with the error (often io.EOF).
+func (b *RuneBuffer) ReadString(delim rune) (string, error) {
+	var sb strings.Builder
+	for {
+		r, _, err := b.ReadRune()
+		if err != nil {
+			return sb.String(), err
+		}
+		sb.WriteRune(r)
+		if r == delim {
+			return sb.String(), nil
+		}
+	}
+}
+
+// ReadLine reads a single line, stopping at and discarding a trailing '\n'
+// (and, if present, a preceding '\r'). It mirrors the low-level
+// bufio.Reader.ReadLine: isPrefix is true when the ring fills before a
+// newline is found and more of the stream follows, meaning the caller must
+// call ReadLine again to fetch the remainder of the line. A fragment that
+// fills the ring and is followed by the end of the stream is returned with
+// isPrefix false.
+func (b *RuneBuffer) ReadLine() (line string, isPrefix bool, err error) {
+	var sb strings.Builder
+	var count int
+	for {
+		r, _, rerr := b.ReadRune()
+		if rerr != nil {
+			if rerr == io.EOF && count > 0 {
+				return sb.String(), false, nil
+			}
+			return sb.String(), false, rerr
+		}
+		if r == '\n' {
+			return strings.TrimSuffix(sb.String(), "\r"), false, nil
+		}
+		sb.WriteRune(r)
+		count++
+		if count >= len(b.buf) {
+			if r == '\r' {
+				// Hold the trailing \r back for the next call, so a \n that
+				// arrives just after the ring fills is still recognized as
+				// part of the same \r\n terminator instead of starting a new
+				// fragment on its own.
+				b.UnreadRuneNoFail()
+				return strings.TrimSuffix(sb.String(), "\r"), true, nil
+			}
+			if next, perr := b.PeekRunes(1); perr == nil && len(next) == 0 {
+				return sb.String(), false, nil
+			}
+			return sb.String(), true, nil
+		}
+	}
+}
+
+// Pos returns the position just past the most recently read rune, which is
+// the position of the next rune to be read.
+func (b *RuneBuffer) Pos() Position {
+	return b.pos
+}
+
+// String returns the runes read since the last Emit or Discard call, without
+// consuming them or affecting the emit cursor. Only runes still held in the
+// ring are returned: if the span is longer than the ring's capacity, or
+// lookahead pulled in by PeekRunes has overwritten its start, only its most
+// recent runes are returned.
+func (b *RuneBuffer) String() string {
+	n := b.pending
+	if held := b.size - b.unread; n > held {
+		n = held
+	}
+	if n <= 0 {
+		return ""
+	}
+	var sb strings.Builder
+	p := b.normalizePtr(b.rptr - n)
+	for i := 0; i < n; i++ {
+		sb.WriteRune(b.buf[p])
+		p = b.normalizePtr(p + 1)
+	}
+	return sb.String()
+}
+
+// Emit returns String and advances the emit cursor to the current read
+// position, marking the accumulated runes as consumed into a token.
+func (b *RuneBuffer) Emit() string {
+	s := b.String()
+	b.pending = 0
+	return s
+}
+
+// Discard drops the runes accumulated since the last Emit or Discard without
+// returning them, advancing the emit cursor to the current read position.
+func (b *RuneBuffer) Discard() {
+	b.pending = 0
+}
+
+// UnreadRune unreads the previously read rune, satisfying io.RuneScanner. It
+// returns ErrInvalidUnreadRune if the last ReadRune failed, if nothing has
+// been read since the buffer was created or Reset, if an unread
+// (UnreadRune, UnreadRuneNoFail or UnreadNumRunes) has happened since the
+// last ReadRune, or if the ring has no unread capacity left to restore into.
+func (b *RuneBuffer) UnreadRune() error {
+	if !b.canUnread {
+		return ErrInvalidUnreadRune
+	}
+	if b.unreadRunes(1) == 0 {
+		return ErrInvalidUnreadRune
+	}
+	b.canUnread = false
+	return nil
+}
+
+// UnreadRuneNoFail unreads the previously read rune, if it exists.
 // If no runes have been read, or the read pointer has reached the beginning of the buffer, this is a no-op.
-func (b *RuneBuffer) UnreadRune() {
+func (b *RuneBuffer) UnreadRuneNoFail() {
 	b.unreadRunes(1)
 }
 
-// UnreadNumRunes will unread the specified number of runes.
+// UnreadNumRunes will unread the specified number of runes. If AutoGrow is
+// set and the buffered lookahead plus num would exceed the ring's current
+// capacity, the ring is first grown to that total (capped at MaxSize, if
+// set). Growing keeps only the runes the ring already holds, so the unread
+// still stops at the oldest rune held or at the emit cursor.
 func (b *RuneBuffer) UnreadNumRunes(num int) {
+	if b.AutoGrow {
+		if want := b.unread + num; want > len(b.buf) {
+			if b.MaxSize > 0 && want > b.MaxSize {
+				want = b.MaxSize
+			}
+			b.Grow(want)
+		}
+	}
 	b.unreadRunes(num)
 }
 
-func (b *RuneBuffer) unreadRunes(num int) {
+// Grow increases the ring's capacity to at least n, preserving its current
+// contents (both the runes already read and available to unread, and any
+// buffered-but-unread lookahead) by copying them into a new slice and
+// renormalizing rptr/wptr to the start of it. It is a no-op if the ring
+// already has capacity n or more.
+func (b *RuneBuffer) Grow(n int) {
+	if n <= len(b.buf) {
+		return
+	}
+	wEnd := b.normalizePtr(b.rptr + b.unread)
+	start := b.normalizePtr(wEnd - b.size)
+	newBuf := make([]rune, n)
+	newPosRing := make([]Position, n)
+	if start < wEnd || (start == wEnd && b.size == 0) {
+		copy(newBuf, b.buf[start:wEnd])
+		copy(newPosRing, b.posRing[start:wEnd])
+	} else {
+		k := copy(newBuf, b.buf[start:])
+		copy(newBuf[k:], b.buf[:wEnd])
+		k = copy(newPosRing, b.posRing[start:])
+		copy(newPosRing[k:], b.posRing[:wEnd])
+	}
+	b.buf = newBuf
+	b.posRing = newPosRing
+	b.rptr = b.size - b.unread
+	if b.wptr != -1 {
+		b.wptr = b.size
+	}
+	// The freshly copied window is laid out linearly from index 0, so at
+	// most rptr runes of pending history can be behind the read pointer.
+	if b.pending > b.rptr {
+		b.pending = b.rptr
+	}
+}
+
+// unreadRunes unreads up to num runes, stopping early if the ring runs out of
+// unread capacity or would cross the emit cursor. It returns the number of
+// runes actually unread.
+func (b *RuneBuffer) unreadRunes(num int) int {
+	b.canUnread = false
 	for i := 0; i < num; i++ {
 		if b.size == 0 {
-			return
+			return i
 		}
 		if b.unread == b.size {
-			return
+			return i
+		}
+		if b.pending == 0 {
+			return i
 		}
 		b.incrementUnread()
 		b.decrementRptr()
+		b.pos = b.posRing[b.rptr]
+		b.pending--
 	}
+	return num
+}
+
+// advancePosition returns the position that follows r, given that pos was
+// the position of r itself (i.e. the position recorded just before r was
+// consumed).
+func advancePosition(pos Position, r rune) Position {
+	pos.Offset++
+	if r == '\n' {
+		pos.Line++
+		pos.Column = 0
+	} else {
+		pos.Column++
+	}
+	return pos
 }
 
 func (b *RuneBuffer) readStream() error {
 	r, _, err := b.br.ReadRune()
-	if err != nil && err != io.EOF {
+	if err != nil {
+		if err == io.EOF {
+			// Park the write pointer so ReadRune sees it hit EOF.
+			b.wptr = -1
+		}
 		return err
 	}
 	b.buf[b.wptr] = r
@@ -88,10 +410,6 @@ func (b *RuneBuffer) readStream() error {
 	}
 	b.incrementWptr()
 	b.incrementSize()
-	if r == 0 {
-		// Park the write pointer so read sees it hit EOF.
-		b.wptr = -1
-	}
 	return nil
 }
 
@@ -144,16 +462,24 @@ type ThreadSafeRuneBuffer struct {
 	mux sync.Mutex
 }
 
-func (t *ThreadSafeRuneBuffer) ReadRune() (rune, error) {
+var _ io.RuneScanner = (*ThreadSafeRuneBuffer)(nil)
+
+func (t *ThreadSafeRuneBuffer) ReadRune() (rune, int, error) {
 	t.mux.Lock()
 	defer t.mux.Unlock()
 	return t.RuneBuffer.ReadRune()
 }
 
-func (t *ThreadSafeRuneBuffer) UnreadRune() {
+func (t *ThreadSafeRuneBuffer) UnreadRune() error {
 	t.mux.Lock()
 	defer t.mux.Unlock()
-	t.RuneBuffer.UnreadRune()
+	return t.RuneBuffer.UnreadRune()
+}
+
+func (t *ThreadSafeRuneBuffer) UnreadRuneNoFail() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.RuneBuffer.UnreadRuneNoFail()
 }
 
 func (t *ThreadSafeRuneBuffer) UnreadNumRunes(num int) {
@@ -161,3 +487,63 @@ func (t *ThreadSafeRuneBuffer) UnreadNumRunes(num int) {
 	defer t.mux.Unlock()
 	t.RuneBuffer.UnreadNumRunes(num)
 }
+
+func (t *ThreadSafeRuneBuffer) PeekRunes(n int) ([]rune, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.RuneBuffer.PeekRunes(n)
+}
+
+func (t *ThreadSafeRuneBuffer) ReadRuneSlice(delim rune) ([]rune, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.RuneBuffer.ReadRuneSlice(delim)
+}
+
+func (t *ThreadSafeRuneBuffer) ReadString(delim rune) (string, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.RuneBuffer.ReadString(delim)
+}
+
+func (t *ThreadSafeRuneBuffer) ReadLine() (string, bool, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.RuneBuffer.ReadLine()
+}
+
+func (t *ThreadSafeRuneBuffer) Pos() Position {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.RuneBuffer.Pos()
+}
+
+func (t *ThreadSafeRuneBuffer) String() string {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.RuneBuffer.String()
+}
+
+func (t *ThreadSafeRuneBuffer) Emit() string {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.RuneBuffer.Emit()
+}
+
+func (t *ThreadSafeRuneBuffer) Discard() {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.RuneBuffer.Discard()
+}
+
+func (t *ThreadSafeRuneBuffer) Reset(r io.Reader) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.RuneBuffer.Reset(r)
+}
+
+func (t *ThreadSafeRuneBuffer) Grow(n int) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.RuneBuffer.Grow(n)
+}
@@ -2,6 +2,7 @@ package runebuffer
 
 import (
 	"github.com/stretchr/testify/assert"
+	"io"
 	"strings"
 	"testing"
 )
@@ -11,13 +12,13 @@ func TestScenario(t *testing.T) {
 	ring := NewRuneBufferWithSize(reader, 3)
 
 	t.Run("Can't unread with an empty buffer", func(t *testing.T) {
-		ring.UnreadRune()
+		_ = ring.UnreadRune()
 		assert.Equal(t, 0, ring.rptr)
 		assert.Equal(t, 0, ring.wptr)
 	})
 
 	t.Run("Reading the first rune in the stream sets index 0 of the buffer", func(t *testing.T) {
-		read, err := ring.ReadRune()
+		read, _, err := ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'A', read)
 		assert.Equal(t, 1, ring.rptr)
@@ -27,7 +28,7 @@ func TestScenario(t *testing.T) {
 	t.Run("Can't unread beyond buffer size", func(t *testing.T) {
 		ring.UnreadNumRunes(100)
 		assert.Equal(t, 0, ring.rptr)
-		read, err := ring.ReadRune()
+		read, _, err := ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'A', read)
 		assert.Equal(t, 1, ring.rptr)
@@ -35,7 +36,7 @@ func TestScenario(t *testing.T) {
 	})
 
 	t.Run("Reading the second rune continues the readStream + read", func(t *testing.T) {
-		read, err := ring.ReadRune()
+		read, _, err := ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'B', read)
 		assert.Equal(t, 2, ring.rptr)
@@ -45,13 +46,13 @@ func TestScenario(t *testing.T) {
 	t.Run("Unread twice and read 3", func(t *testing.T) {
 		ring.UnreadNumRunes(2)
 		assert.Equal(t, 0, ring.rptr)
-		read, err := ring.ReadRune()
+		read, _, err := ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'A', read)
-		read, err = ring.ReadRune()
+		read, _, err = ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'B', read)
-		read, err = ring.ReadRune()
+		read, _, err = ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'C', read)
 		assert.Equal(t, 0, ring.rptr)
@@ -66,28 +67,376 @@ func TestScenario(t *testing.T) {
 		assert.Equal(t, 1, ring.wptr)
 	})
 
-	t.Run("Reading to EOF will return 0 and nil error", func(t *testing.T) {
-		read, err := ring.ReadRune()
+	t.Run("Reading through to EOF surfaces a sticky io.EOF", func(t *testing.T) {
+		read, _, err := ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'B', read)
-		read, err = ring.ReadRune()
+		read, _, err = ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'C', read)
-		read, err = ring.ReadRune()
+		read, _, err = ring.ReadRune()
 		assert.NoError(t, err)
 		assert.Equal(t, 'D', read)
-		read, err = ring.ReadRune()
-		assert.NoError(t, err)
+		read, _, err = ring.ReadRune()
+		assert.Equal(t, io.EOF, err)
 		assert.Equal(t, rune(0), read)
-		assert.Equal(t, 2, ring.rptr)
+		assert.Equal(t, 1, ring.rptr)
 		assert.Equal(t, -1, ring.wptr)
 	})
 
-	t.Run("Subsequent reads from buffer will continue to return 0 and nil error", func(t *testing.T) {
-		read, err := ring.ReadRune()
-		assert.NoError(t, err)
+	t.Run("Subsequent reads from buffer will continue to return io.EOF", func(t *testing.T) {
+		read, _, err := ring.ReadRune()
+		assert.Equal(t, io.EOF, err)
 		assert.Equal(t, rune(0), read)
-		assert.Equal(t, 2, ring.rptr)
+		assert.Equal(t, 1, ring.rptr)
 		assert.Equal(t, -1, ring.wptr)
 	})
 }
+
+func TestLexerAPI(t *testing.T) {
+	t.Run("PeekRunes returns upcoming runes without consuming them", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 3)
+
+		peeked, err := ring.PeekRunes(2)
+		assert.NoError(t, err)
+		assert.Equal(t, []rune{'A', 'B'}, peeked)
+
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+	})
+
+	t.Run("PeekRunes returns a short read at EOF", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB"), 3)
+
+		peeked, err := ring.PeekRunes(3)
+		assert.NoError(t, err)
+		assert.Equal(t, []rune{'A', 'B'}, peeked)
+	})
+
+	t.Run("PeekRunes fails with ErrBufferFull rather than truncating", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 3)
+
+		peeked, err := ring.PeekRunes(10)
+		assert.ErrorIs(t, err, ErrBufferFull)
+		assert.Nil(t, peeked)
+	})
+
+	t.Run("Emit returns accumulated runes and resets the span", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 3)
+
+		_, _, _ = ring.ReadRune()
+		_, _, _ = ring.ReadRune()
+		assert.Equal(t, "AB", ring.String())
+		assert.Equal(t, "AB", ring.Emit())
+		assert.Equal(t, "", ring.String())
+	})
+
+	t.Run("Discard drops the accumulated span without returning it", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 3)
+
+		_, _, _ = ring.ReadRune()
+		ring.Discard()
+		assert.Equal(t, "", ring.String())
+	})
+
+	t.Run("UnreadRune cannot cross the emit cursor", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 3)
+
+		_, _, _ = ring.ReadRune()
+		ring.Emit()
+		err := ring.UnreadRune()
+		assert.ErrorIs(t, err, ErrInvalidUnreadRune)
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'B', read)
+	})
+
+	t.Run("Plain ring usage still allows unreading across a full wrap when Emit is never called", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCDEF"), 3)
+
+		_, _, _ = ring.ReadRune()
+		_, _, _ = ring.ReadRune()
+		_, _, _ = ring.ReadRune()
+		ring.UnreadNumRunes(3)
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+	})
+
+	t.Run("Pos tracks offset, line and column and is reversible via UnreadRune", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB\nCD"), 5)
+
+		_, _, _ = ring.ReadRune()
+		_, _, _ = ring.ReadRune()
+		_, _, _ = ring.ReadRune()
+		assert.Equal(t, Position{Offset: 3, Line: 2, Column: 0}, ring.Pos())
+
+		assert.NoError(t, ring.UnreadRune())
+		assert.Equal(t, Position{Offset: 2, Line: 1, Column: 2}, ring.Pos())
+	})
+}
+
+func TestRuneScannerContract(t *testing.T) {
+	t.Run("RuneBuffer satisfies io.RuneScanner", func(t *testing.T) {
+		var _ io.RuneScanner = NewRuneBuffer(strings.NewReader(""))
+	})
+
+	t.Run("UnreadRune errors with no prior ReadRune", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB"), 2)
+		assert.ErrorIs(t, ring.UnreadRune(), ErrInvalidUnreadRune)
+	})
+
+	t.Run("A second consecutive UnreadRune errors", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB"), 2)
+		_, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.NoError(t, ring.UnreadRune())
+		assert.ErrorIs(t, ring.UnreadRune(), ErrInvalidUnreadRune)
+	})
+
+	t.Run("EOF is sticky across repeated ReadRune calls", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("A"), 2)
+		_, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		_, _, err = ring.ReadRune()
+		assert.Equal(t, io.EOF, err)
+		_, _, err = ring.ReadRune()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("UnreadRune errors after an intervening failed ReadRune", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("A"), 2)
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+
+		_, _, err = ring.ReadRune()
+		assert.Equal(t, io.EOF, err)
+
+		assert.ErrorIs(t, ring.UnreadRune(), ErrInvalidUnreadRune)
+	})
+}
+
+func TestScanningHelpers(t *testing.T) {
+	t.Run("ReadRuneSlice reads up to and including the delimiter", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("foo,bar"), 8)
+
+		slice, err := ring.ReadRuneSlice(',')
+		assert.NoError(t, err)
+		assert.Equal(t, []rune("foo,"), slice)
+	})
+
+	t.Run("ReadRuneSlice fails with ErrBufferFull when the ring fills before the delimiter", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("foobarbaz,"), 4)
+
+		slice, err := ring.ReadRuneSlice(',')
+		assert.ErrorIs(t, err, ErrBufferFull)
+		assert.Equal(t, []rune("foob"), slice)
+	})
+
+	t.Run("ReadRuneSlice returns data read so far and io.EOF when the delimiter is never found", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("foo"), 8)
+
+		slice, err := ring.ReadRuneSlice(',')
+		assert.Equal(t, io.EOF, err)
+		assert.Equal(t, []rune("foo"), slice)
+	})
+
+	t.Run("ReadString mirrors bufio.Reader.ReadString", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("foo,bar"), 4)
+
+		s, err := ring.ReadString(',')
+		assert.NoError(t, err)
+		assert.Equal(t, "foo,", s)
+	})
+
+	t.Run("ReadString is not bounded by the ring's capacity", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("foobarbaz,"), 4)
+
+		s, err := ring.ReadString(',')
+		assert.NoError(t, err)
+		assert.Equal(t, "foobarbaz,", s)
+	})
+
+	t.Run("ReadLine strips the trailing newline and carriage return", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("foo\r\nbar"), 8)
+
+		line, isPrefix, err := ring.ReadLine()
+		assert.NoError(t, err)
+		assert.False(t, isPrefix)
+		assert.Equal(t, "foo", line)
+	})
+
+	t.Run("ReadLine sets isPrefix when the ring fills before a newline is found", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("foobarbaz\n"), 4)
+
+		line, isPrefix, err := ring.ReadLine()
+		assert.NoError(t, err)
+		assert.True(t, isPrefix)
+		assert.Equal(t, "foob", line)
+	})
+
+	t.Run("ReadLine returns the final fragment without error at EOF", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("foo"), 8)
+
+		line, isPrefix, err := ring.ReadLine()
+		assert.NoError(t, err)
+		assert.False(t, isPrefix)
+		assert.Equal(t, "foo", line)
+
+		_, _, err = ring.ReadLine()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("PeekRunes rejects a negative count instead of panicking", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 4)
+
+		peeked, err := ring.PeekRunes(-1)
+		assert.ErrorIs(t, err, ErrNegativeCount)
+		assert.Nil(t, peeked)
+	})
+
+	t.Run("ReadLine holds back a trailing \\r that lands on the ring boundary", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("abc\r\nrest"), 4)
+
+		line, isPrefix, err := ring.ReadLine()
+		assert.NoError(t, err)
+		assert.True(t, isPrefix)
+		assert.Equal(t, "abc", line)
+
+		line, isPrefix, err = ring.ReadLine()
+		assert.NoError(t, err)
+		assert.False(t, isPrefix)
+		assert.Equal(t, "", line)
+
+		line, isPrefix, err = ring.ReadLine()
+		assert.NoError(t, err)
+		assert.False(t, isPrefix)
+		assert.Equal(t, "rest", line)
+	})
+
+	t.Run("UnreadNumRunes still works on data returned by the lookahead calls", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 4)
+
+		peeked, err := ring.PeekRunes(2)
+		assert.NoError(t, err)
+		assert.Equal(t, []rune{'A', 'B'}, peeked)
+
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+
+		ring.UnreadNumRunes(1)
+		read, _, err = ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+	})
+}
+
+func TestGrow(t *testing.T) {
+	t.Run("Grow below the current capacity is a no-op", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB"), 4)
+		ring.Grow(2)
+		assert.Equal(t, 4, len(ring.buf))
+	})
+
+	t.Run("Grow preserves buffered lookahead that hasn't been read yet", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 3)
+
+		peeked, err := ring.PeekRunes(2)
+		assert.NoError(t, err)
+		assert.Equal(t, []rune{'A', 'B'}, peeked)
+
+		ring.Grow(6)
+		assert.Equal(t, 6, len(ring.buf))
+
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+		read, _, err = ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'B', read)
+	})
+
+	t.Run("Grow across the wrap-around boundary keeps both history and lookahead in order", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABCD"), 3)
+
+		// Wrap the ring by reading through its whole capacity once.
+		for _, want := range []rune{'A', 'B', 'C'} {
+			read, _, err := ring.ReadRune()
+			assert.NoError(t, err)
+			assert.Equal(t, want, read)
+		}
+
+		// Peek the next rune so it's buffered as unread lookahead, which
+		// overwrites the now-stale 'A' slot ('A' has already been read).
+		peeked, err := ring.PeekRunes(1)
+		assert.NoError(t, err)
+		assert.Equal(t, []rune{'D'}, peeked)
+
+		ring.Grow(6)
+		assert.Equal(t, 6, len(ring.buf))
+
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'D', read)
+
+		// B and C are still recoverable; A was already overwritten before Grow.
+		ring.UnreadNumRunes(2)
+		read, _, err = ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'C', read)
+		read, _, err = ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'D', read)
+	})
+
+	t.Run("Grow leaves a parked EOF state intact", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("ABC"), 3)
+
+		for _, want := range []rune{'A', 'B', 'C'} {
+			read, _, err := ring.ReadRune()
+			assert.NoError(t, err)
+			assert.Equal(t, want, read)
+		}
+		_, _, err := ring.ReadRune()
+		assert.Equal(t, io.EOF, err)
+
+		ring.Grow(6)
+		assert.Equal(t, 6, len(ring.buf))
+
+		_, _, err = ring.ReadRune()
+		assert.Equal(t, io.EOF, err)
+
+		ring.UnreadNumRunes(3)
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+	})
+
+	t.Run("AutoGrow expands the ring when UnreadNumRunes exceeds its capacity", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB"), 3)
+		ring.AutoGrow = true
+
+		read, _, err := ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+
+		ring.UnreadNumRunes(5)
+		assert.Equal(t, 5, len(ring.buf))
+		read, _, err = ring.ReadRune()
+		assert.NoError(t, err)
+		assert.Equal(t, 'A', read)
+	})
+
+	t.Run("AutoGrow is capped by MaxSize", func(t *testing.T) {
+		ring := NewRuneBufferWithSize(strings.NewReader("AB"), 3)
+		ring.AutoGrow = true
+		ring.MaxSize = 4
+
+		_, _, _ = ring.ReadRune()
+		ring.UnreadNumRunes(10)
+		assert.Equal(t, 4, len(ring.buf))
+	})
+}